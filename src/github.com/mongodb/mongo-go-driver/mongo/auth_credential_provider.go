@@ -0,0 +1,44 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import "context"
+
+// resolveCredentials returns the username/password to authenticate with,
+// preferring a configured CredentialProvider over any credentials embedded
+// in the connection string so that short-lived secrets from a vault-like
+// source always win.
+func (c *Client) resolveCredentials(ctx context.Context) (username, password string, err error) {
+	if c.credentialProvider == nil {
+		return c.connString.Username, c.connString.Password, nil
+	}
+
+	return c.credentialProvider.Credentials(ctx)
+}
+
+// authenticateWithRetry performs the authentication handshake against db
+// and, when retryOnAuthFailure is set, re-resolves credentials from the
+// CredentialProvider and retries exactly once on failure. It is called only
+// from Connect.
+func (c *Client) authenticateWithRetry(ctx context.Context, db *Database) error {
+	username, password, err := c.resolveCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.authenticate(ctx, db, username, password)
+	if err == nil || !c.retryOnAuthFailure || c.credentialProvider == nil {
+		return err
+	}
+
+	username, password, err = c.resolveCredentials(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.authenticate(ctx, db, username, password)
+}
@@ -0,0 +1,135 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mongodb/mongo-go-driver/mongo/clientopt"
+)
+
+// oidcMechanism is the authMechanism value that selects MONGODB-OIDC.
+const oidcMechanism = "MONGODB-OIDC"
+
+// reauthenticationRequired is the server error code returned when a
+// previously-accepted OIDC access token has expired or been revoked and the
+// driver must invoke the callback again before retrying the operation.
+const reauthenticationRequired = 391
+
+// ErrOIDCCallbackRequired is returned when MONGODB-OIDC is selected but the
+// client was not configured with an OIDCCallback and no OIDC_TOKEN_FILE
+// machine-workflow token is available.
+var ErrOIDCCallbackRequired = errors.New("mongo: MONGODB-OIDC requires clientopt.OIDCCallback or OIDC_TOKEN_FILE")
+
+// oidcAuthenticator performs SASL saslStart/saslContinue conversations for
+// MONGODB-OIDC and caches the resulting credential per Client so that
+// subsequent connections in the pool don't re-invoke the callback.
+type oidcAuthenticator struct {
+	callback clientopt.OIDCCallbackFunc
+
+	mu    sync.Mutex
+	cred  clientopt.OIDCCredential
+	valid bool
+}
+
+func newOIDCAuthenticator(callback clientopt.OIDCCallbackFunc) *oidcAuthenticator {
+	return &oidcAuthenticator{callback: callback}
+}
+
+// token returns a cached access token if one is present and not near expiry,
+// otherwise it obtains a new one from the configured callback, falling back
+// to the machine workflow (OIDC_TOKEN_FILE) when no callback is configured.
+func (a *oidcAuthenticator) token(ctx context.Context, info clientopt.IDPInfo) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.valid && (a.cred.ExpiresAt.IsZero() || time.Now().Before(a.cred.ExpiresAt.Add(-30*time.Second))) {
+		return a.cred.AccessToken, nil
+	}
+
+	cred, err := a.refresh(ctx, info)
+	if err != nil {
+		return "", err
+	}
+
+	a.cred = cred
+	a.valid = true
+	return cred.AccessToken, nil
+}
+
+// invalidate drops the cached credential, forcing the next token() call to
+// invoke the callback again. It is called when the server responds with
+// ReauthenticationRequired.
+func (a *oidcAuthenticator) invalidate() {
+	a.mu.Lock()
+	a.valid = false
+	a.mu.Unlock()
+}
+
+func (a *oidcAuthenticator) refresh(ctx context.Context, info clientopt.IDPInfo) (clientopt.OIDCCredential, error) {
+	if a.callback != nil {
+		return a.callback(ctx, info)
+	}
+
+	if tokenFile := os.Getenv("OIDC_TOKEN_FILE"); tokenFile != "" {
+		data, err := ioutil.ReadFile(tokenFile)
+		if err != nil {
+			return clientopt.OIDCCredential{}, err
+		}
+		return clientopt.OIDCCredential{AccessToken: strings.TrimSpace(string(data))}, nil
+	}
+
+	return clientopt.OIDCCredential{}, ErrOIDCCallbackRequired
+}
+
+// authenticateOIDC runs the saslStart/saslContinue conversation for
+// MONGODB-OIDC against db, retrying once with a freshly minted token if the
+// server reports ReauthenticationRequired.
+func (c *Client) authenticateOIDC(ctx context.Context, db *Database) error {
+	if c.oidcAuth == nil {
+		c.oidcAuth = newOIDCAuthenticator(c.oidcCallback)
+	}
+
+	info := clientopt.IDPInfo{Issuer: c.connString.AuthMechanismProperties["OIDC_ISSUER"]}
+
+	token, err := c.oidcAuth.token(ctx, info)
+	if err != nil {
+		return err
+	}
+
+	if err := c.runOIDCSASLConversation(ctx, db, token); err != nil {
+		if isReauthenticationRequired(err) {
+			c.oidcAuth.invalidate()
+			token, err = c.oidcAuth.token(ctx, info)
+			if err != nil {
+				return err
+			}
+			return c.runOIDCSASLConversation(ctx, db, token)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// runOIDCSASLConversation performs the saslStart/saslContinue round trip
+// that authenticates token against $external on behalf of the client.
+func (c *Client) runOIDCSASLConversation(ctx context.Context, db *Database, token string) error {
+	conversation := newSaslConversation(oidcMechanism, []byte(token))
+	return conversation.run(ctx, db)
+}
+
+func isReauthenticationRequired(err error) bool {
+	cmdErr, ok := err.(commandError)
+	return ok && cmdErr.Code == reauthenticationRequired
+}
@@ -0,0 +1,267 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+)
+
+// changeStreamConfig holds the options a changeStreamOption may set on the
+// $changeStream stage opened by newChangeStream.
+type changeStreamConfig struct {
+	resumeAfter bson.Reader
+}
+
+// changeStreamOption configures the $changeStream stage opened by
+// Client.Watch and Client.Subscribe.
+type changeStreamOption func(*changeStreamConfig)
+
+// withResumeAfter resumes the change stream from token instead of starting
+// at the current point in the oplog. A nil token is a no-op, so callers can
+// pass whatever (possibly absent) token a ResumeTokenStore returns.
+func withResumeAfter(token bson.Reader) changeStreamOption {
+	return func(cfg *changeStreamConfig) {
+		if token != nil {
+			cfg.resumeAfter = token
+		}
+	}
+}
+
+// ChangeStream iterates the events produced by an aggregation pipeline
+// opened with a leading $changeStream stage, as returned by Client.Watch.
+type ChangeStream struct {
+	db       *Database
+	target   interface{}
+	cursorID int64
+	batch    []bson.Reader
+	current  bson.Reader
+	token    bson.Reader
+	err      error
+}
+
+// newChangeStream opens a change stream by running the aggregate command
+// against target within db. target is the collection name to scope the
+// stream to a single collection, or int32(1) to watch the whole database or
+// deployment, matching how MongoDB's aggregate command itself scopes a
+// change stream: the aggregate target IS the namespace being watched, no
+// separate $match stage is needed to filter out other collections.
+func newChangeStream(ctx context.Context, db *Database, target interface{}, userPipeline *bson.Array, opts ...changeStreamOption) (*ChangeStream, error) {
+	cfg := &changeStreamConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	changeStreamStage := bson.NewDocument()
+	if cfg.resumeAfter != nil {
+		changeStreamStage.Append(bson.EC.SubDocumentFromReader("resumeAfter", cfg.resumeAfter))
+	}
+
+	pipeline := bson.NewArray(
+		bson.VC.DocumentFromElements(bson.EC.SubDocumentFromReader("$changeStream", changeStreamStage.Reader())),
+	)
+	if userPipeline != nil {
+		elems, _ := userPipeline.Elements()
+		for _, e := range elems {
+			pipeline.Append(e)
+		}
+	}
+
+	rdr, err := db.RunCommand(ctx, bson.NewDocument(
+		bson.EC.Interface("aggregate", target),
+		bson.EC.Array("pipeline", pipeline),
+		bson.EC.SubDocumentFromElements("cursor", bson.EC.Int32("batchSize", 0)),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if cmdErr := commandErrorFromReply(rdr); cmdErr != nil {
+		return nil, cmdErr
+	}
+
+	cs := &ChangeStream{db: db, target: target}
+	if err := cs.consumeCursorDoc(rdr); err != nil {
+		return nil, err
+	}
+
+	if changeStreamOpened != nil {
+		changeStreamOpened(cs)
+	}
+
+	return cs, nil
+}
+
+// changeStreamOpened, when set by a test, is invoked synchronously right
+// after a change stream is successfully opened. It exists so tests can
+// learn that Subscribe's stream is actually live (and which server-side
+// cursor backs it) without exposing either through the public API.
+var changeStreamOpened func(cs *ChangeStream)
+
+// consumeCursorDoc reads the cursor id and next batch out of a command
+// reply that embeds a "cursor" subdocument, the shape returned by both
+// aggregate and getMore.
+func (cs *ChangeStream) consumeCursorDoc(rdr bson.Reader) error {
+	cursorVal, err := rdr.LookupErr("cursor")
+	if err != nil {
+		return err
+	}
+	cursorDoc := cursorVal.Document()
+
+	if idVal, err := cursorDoc.LookupErr("id"); err == nil {
+		cs.cursorID = idVal.Int64()
+	}
+
+	batchVal, err := cursorDoc.LookupErr("firstBatch")
+	if err != nil {
+		batchVal, err = cursorDoc.LookupErr("nextBatch")
+	}
+	if err != nil {
+		return nil
+	}
+
+	elems, err := batchVal.Array().Elements()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range elems {
+		cs.batch = append(cs.batch, e.Value().Document().Reader)
+	}
+
+	return nil
+}
+
+// getMoreMaxTimeMS bounds how long the server blocks an empty getMore
+// waiting for a new event before returning, so Next doesn't busy-loop
+// hammering the server once the current batch is drained and the cursor is
+// otherwise idle.
+const getMoreMaxTimeMS = 1000
+
+// getMore fetches the next batch from the server for the live cursor
+// backing this stream.
+func (cs *ChangeStream) getMore(ctx context.Context) error {
+	// A database- or deployment-wide stream (aggregate: 1) keeps its cursor
+	// on the "$cmd.aggregate" pseudo-collection.
+	collName, ok := cs.target.(string)
+	if !ok {
+		collName = "$cmd.aggregate"
+	}
+
+	rdr, err := cs.db.RunCommand(ctx, bson.NewDocument(
+		bson.EC.Int64("getMore", cs.cursorID),
+		bson.EC.String("collection", collName),
+		bson.EC.Int64("maxTimeMS", getMoreMaxTimeMS),
+	))
+	if err != nil {
+		return err
+	}
+	if cmdErr := commandErrorFromReply(rdr); cmdErr != nil {
+		return cmdErr
+	}
+
+	return cs.consumeCursorDoc(rdr)
+}
+
+// Next advances the stream to the next event, issuing a getMore against the
+// server when the current batch is exhausted. It blocks until an event is
+// available, ctx is done, or the cursor errors, returning false in the
+// latter two cases.
+func (cs *ChangeStream) Next(ctx context.Context) bool {
+	for len(cs.batch) == 0 {
+		select {
+		case <-ctx.Done():
+			cs.err = ctx.Err()
+			return false
+		default:
+		}
+
+		if cs.cursorID == 0 {
+			return false
+		}
+		if err := cs.getMore(ctx); err != nil {
+			cs.err = err
+			return false
+		}
+	}
+
+	cs.current = cs.batch[0]
+	cs.batch = cs.batch[1:]
+
+	if idVal, err := cs.current.Lookup("_id"); err == nil {
+		cs.token = idVal.Value().Document().Reader
+	}
+
+	return true
+}
+
+// Decode unmarshals the current event into event.
+func (cs *ChangeStream) Decode(event *ChangeEvent) error {
+	event.Raw = cs.current
+
+	if idVal, err := cs.current.Lookup("_id"); err == nil {
+		event.ID = idVal.Value().Document().Reader
+	}
+	if opVal, err := cs.current.Lookup("operationType"); err == nil {
+		event.OperationType = opVal.Value().StringValue()
+	}
+	if nsVal, err := cs.current.Lookup("ns"); err == nil {
+		nsDoc := nsVal.Value().Document()
+		if dbVal, err := nsDoc.LookupErr("db"); err == nil {
+			event.Namespace.DB = dbVal.StringValue()
+		}
+		if collVal, err := nsDoc.LookupErr("coll"); err == nil {
+			event.Namespace.Coll = collVal.StringValue()
+		}
+	}
+	if fdVal, err := cs.current.Lookup("fullDocument"); err == nil {
+		event.FullDocument = fdVal.Value().Document().Reader
+	}
+
+	return nil
+}
+
+// ResumeToken returns the resume token of the most recently delivered
+// event, suitable for persisting via a ResumeTokenStore and passing to
+// withResumeAfter to resume the stream later.
+func (cs *ChangeStream) ResumeToken() bson.Reader {
+	return cs.token
+}
+
+// Err returns the error, if any, that caused Next to return false.
+func (cs *ChangeStream) Err() error {
+	return cs.err
+}
+
+// Close terminates the underlying server-side cursor.
+func (cs *ChangeStream) Close(ctx context.Context) error {
+	if cs.cursorID == 0 {
+		return nil
+	}
+
+	collName, ok := cs.target.(string)
+	if !ok {
+		collName = "$cmd.aggregate"
+	}
+
+	_, err := cs.db.RunCommand(ctx, bson.NewDocument(
+		bson.EC.String("killCursors", collName),
+		bson.EC.ArrayFromElements("cursors", bson.VC.Int64(cs.cursorID)),
+	))
+	cs.cursorID = 0
+	return err
+}
+
+// splitNamespace splits a "db.collection" namespace into its two parts.
+func splitNamespace(namespace string) (dbName, collName string) {
+	parts := strings.SplitN(namespace, ".", 2)
+	if len(parts) != 2 {
+		return namespace, ""
+	}
+	return parts[0], parts[1]
+}
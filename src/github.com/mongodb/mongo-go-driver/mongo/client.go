@@ -0,0 +1,190 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+	"github.com/mongodb/mongo-go-driver/core/connstring"
+	"github.com/mongodb/mongo-go-driver/core/readpref"
+	"github.com/mongodb/mongo-go-driver/core/session"
+	"github.com/mongodb/mongo-go-driver/core/topology"
+	"github.com/mongodb/mongo-go-driver/core/uuid"
+	"github.com/mongodb/mongo-go-driver/mongo/clientopt"
+)
+
+// Client is a handle to a MongoDB deployment. Use NewClient or
+// NewClientWithOptions to construct one, then Connect before issuing any
+// operations.
+type Client struct {
+	id             uuid.UUID
+	topology       *topology.Topology
+	connString     connstring.ConnString
+	readPreference *readpref.ReadPref
+	clock          *session.ClusterClock
+	registry       *bson.Registry
+
+	// oidcAuth and oidcCallback support the MONGODB-OIDC auth mechanism; see
+	// auth_oidc.go.
+	oidcAuth     *oidcAuthenticator
+	oidcCallback clientopt.OIDCCallbackFunc
+
+	// dialer overrides the network dialer used by the topology; see
+	// unix_socket.go.
+	dialer clientopt.DialerFunc
+
+	// credentialProvider and retryOnAuthFailure support sourcing credentials
+	// from a secrets manager instead of the connection string; see
+	// auth_credential_provider.go.
+	credentialProvider clientopt.CredentialProvider
+	retryOnAuthFailure bool
+
+	// tlsFIPSMode selects the FIPS-approved TLS backend/cipher list; see
+	// tls_go.go and tls_openssl.go.
+	tlsFIPSMode bool
+}
+
+// NewClient creates a new Client from a "mongodb://" connection string, with
+// no options beyond what the URI itself specifies. Call Connect before
+// using it.
+func NewClient(uri string) (*Client, error) {
+	return NewClientWithOptions(uri)
+}
+
+// NewClientWithOptions creates a new Client from a "mongodb://" connection
+// string plus clientopt.Option overrides, which take precedence over any
+// equivalent URI parameter. Call Connect before using it.
+func NewClientWithOptions(uri string, opts ...clientopt.Option) (*Client, error) {
+	cs, err := connstring.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOpt, err := clientopt.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	applyClientOptions(&cs, clientOpt)
+
+	id, err := uuid.New()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		id:             id,
+		connString:     cs,
+		readPreference: readPreferenceFromConnString(cs),
+		clock:          &session.ClusterClock{},
+		registry:       bson.DefaultRegistry,
+		oidcCallback:   clientOpt.OIDCCallback,
+		dialer:         clientOpt.Dialer,
+
+		credentialProvider: clientOpt.CredentialProvider,
+		retryOnAuthFailure: clientOpt.RetryOnAuthFailure,
+		tlsFIPSMode:        clientOpt.TLSFIPSMode,
+	}, nil
+}
+
+// applyClientOptions merges the accumulated clientopt.Client values onto cs,
+// with an explicit Option always overriding whatever the URI parsed to. An
+// OIDCCallback selects MONGODB-OIDC only when the URI names no mechanism.
+func applyClientOptions(cs *connstring.ConnString, opt *clientopt.Client) {
+	if opt.OIDCCallback != nil && cs.AuthMechanism == "" {
+		cs.AuthMechanism = oidcMechanism
+	}
+	if opt.ReplicaSet != "" {
+		cs.ReplicaSet = opt.ReplicaSet
+	}
+	if opt.MaxConnIdleTime != 0 {
+		cs.MaxConnIdleTime = opt.MaxConnIdleTime
+	}
+	if opt.LocalThreshold != 0 {
+		cs.LocalThreshold = opt.LocalThreshold
+	}
+	if opt.ServerSelectionTimeout != 0 {
+		cs.ServerSelectionTimeout = opt.ServerSelectionTimeout
+	}
+}
+
+// readPreferenceFromConnString builds the client's default read preference
+// from the connection string, falling back to Primary when none was given.
+func readPreferenceFromConnString(cs connstring.ConnString) *readpref.ReadPref {
+	if cs.ReadPreference == "" {
+		return readpref.Primary()
+	}
+
+	var opts []readpref.Option
+	if len(cs.ReadPreferenceTagSets) > 0 {
+		opts = append(opts, readpref.WithTagSets(cs.ReadPreferenceTagSets...))
+	}
+	if cs.MaxStalenessSet {
+		opts = append(opts, readpref.WithMaxStaleness(cs.MaxStaleness))
+	}
+
+	rp, err := readpref.New(readpref.Mode(cs.ReadPreference), opts...)
+	if err != nil {
+		return readpref.Primary()
+	}
+	return rp
+}
+
+// Connect establishes the client's connection(s) to its deployment and, for
+// MONGODB-OIDC, authenticates against $external before returning.
+func (c *Client) Connect(ctx context.Context) error {
+	topOpts := []topology.Option{
+		topology.WithConnString(c.connString),
+		topology.WithDialer(c.dialerFor()),
+	}
+
+	if c.connString.SSL {
+		tlsWrapper, err := newTLSWrapper(c.tlsFIPSMode, c.connString)
+		if err != nil {
+			return err
+		}
+		topOpts = append(topOpts, topology.WithTLSWrapper(tlsWrapper))
+	}
+
+	top, err := topology.New(topOpts...)
+	if err != nil {
+		return err
+	}
+	if err := top.Connect(ctx); err != nil {
+		return err
+	}
+	c.topology = top
+
+	external := c.Database("$external")
+
+	if c.connString.AuthMechanism == oidcMechanism {
+		return c.authenticateOIDC(ctx, external)
+	}
+
+	if c.connString.AuthMechanism != "" || c.connString.Username != "" || c.credentialProvider != nil {
+		return c.authenticateWithRetry(ctx, external)
+	}
+
+	return nil
+}
+
+// authenticate runs the SASL conversation for the client's non-OIDC auth
+// mechanism (defaulting to SCRAM-SHA-256) against db using username and
+// password. MONGODB-OIDC is handled separately by authenticateOIDC, since
+// it carries a token rather than a password.
+func (c *Client) authenticate(ctx context.Context, db *Database, username, password string) error {
+	if username == "" && password == "" {
+		return nil
+	}
+
+	mechanism := c.connString.AuthMechanism
+	if mechanism == "" {
+		mechanism = "SCRAM-SHA-256"
+	}
+
+	return newSaslConversation(mechanism, []byte(password)).run(ctx, db)
+}
@@ -8,8 +8,11 @@ package mongo
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
 	"path"
+	"strings"
+	"sync"
 	"testing"
 
 	"fmt"
@@ -423,6 +426,150 @@ func TestClient_Ping_DefaultReadPreference(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestClient_Ping_UnixSocket(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	sockPath := os.Getenv("MONGO_GO_DRIVER_UNIX_SOCKET")
+
+	if len(sockPath) == 0 {
+		t.Skip()
+	}
+
+	cs := fmt.Sprintf("mongodb://%s", strings.Replace(sockPath, "/", "%2F", -1))
+
+	c, err := NewClient(cs)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	err = c.Connect(ctx)
+	require.NoError(t, err)
+
+	err = c.Ping(ctx, nil)
+	require.NoError(t, err)
+}
+
+type rotatingCredentialProvider struct {
+	mu       sync.Mutex
+	username string
+	password string
+}
+
+func (p *rotatingCredentialProvider) Credentials(_ context.Context) (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.username, p.password, nil
+}
+
+func (p *rotatingCredentialProvider) rotate(username, password string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.username = username
+	p.password = password
+}
+
+func TestClient_CredentialProvider_Rotation(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	if os.Getenv("AUTH") != "auth" {
+		t.Skip()
+	}
+
+	provider := &rotatingCredentialProvider{username: "user", password: "pencil"}
+
+	baseConnString := testutil.ConnString(t)
+	c, err := NewClientWithOptions(baseConnString.String(), clientopt.WithCredentialProvider(provider, true))
+	require.NoError(t, err)
+
+	err = c.Connect(ctx)
+	require.NoError(t, err)
+
+	err = c.Ping(ctx, nil)
+	require.NoError(t, err)
+
+	// Credentials rotate after Connect. Authentication, and with it the
+	// provider call and its retry, runs only in Connect, so this Ping
+	// neither consults the provider nor re-authenticates.
+	provider.rotate("user", "pencil-rotated")
+
+	err = c.Ping(ctx, nil)
+	require.NoError(t, err)
+}
+
+func TestClient_Subscribe_ResumesAfterCursorKill(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	c := createTestClient(t)
+	dbName := "subscribe_resume"
+	coll := c.Database(dbName).Collection("events")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// changeStreamOpened fires once Subscribe's change stream is actually
+	// live, giving the test both a real readiness signal and the cursor id
+	// it needs to kill below, instead of guessing at a sleep duration or a
+	// single-goroutine channel self-signal.
+	opened := make(chan int64, 1)
+	changeStreamOpened = func(cs *ChangeStream) {
+		select {
+		case opened <- cs.cursorID:
+		default:
+		}
+	}
+	defer func() { changeStreamOpened = nil }()
+
+	events := make(chan ChangeEvent, 16)
+
+	go func() {
+		_ = c.Subscribe(ctx, dbName+".events", func(e ChangeEvent) {
+			events <- e
+		}, nil)
+	}()
+
+	var cursorID int64
+	select {
+	case cursorID = <-opened:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for change stream to open")
+	}
+
+	_, err := coll.InsertOne(context.Background(), bson.NewDocument(bson.EC.Int32("x", 1)))
+	require.NoError(t, err)
+
+	select {
+	case <-events:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for first change event")
+	}
+
+	// Simulate the cursor being killed out from under the stream; Subscribe
+	// should reconnect using the persisted resume token rather than losing
+	// subsequent events.
+	_, _ = c.Database(dbName).RunCommand(context.Background(), bson.NewDocument(
+		bson.EC.String("killCursors", "events"),
+		bson.EC.ArrayFromElements("cursors", bson.VC.Int64(cursorID)),
+	))
+
+	_, err = coll.InsertOne(context.Background(), bson.NewDocument(bson.EC.Int32("x", 2)))
+	require.NoError(t, err)
+
+	select {
+	case <-events:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for post-resume change event")
+	}
+}
+
 func TestClient_Ping_InvalidHost(t *testing.T) {
 	c, err := NewClientWithOptions("mongodb://nohost:27017", clientopt.ServerSelectionTimeout(1*time.Millisecond))
 	require.NoError(t, err)
@@ -434,3 +581,99 @@ func TestClient_Ping_InvalidHost(t *testing.T) {
 	err = c.Ping(ctx, nil)
 	require.NotNil(t, err)
 }
+
+func TestClient_OIDCAuth(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	tokenFile := os.Getenv("OIDC_TOKEN_FILE")
+
+	if len(tokenFile) == 0 || os.Getenv("AUTH") == "auth" {
+		t.Skip()
+	}
+
+	const user = "test-oidc-user"
+
+	c := createTestClient(t)
+	db := c.Database("$external")
+
+	// We don't care if the user doesn't already exist.
+	_, _ = db.RunCommand(
+		context.Background(),
+		bson.NewDocument(
+			bson.EC.String("dropUser", user),
+		),
+	)
+
+	_, err := db.RunCommand(
+		context.Background(),
+		bson.NewDocument(
+			bson.EC.String("createUser", user),
+			bson.EC.ArrayFromElements("roles",
+				bson.VC.DocumentFromElements(
+					bson.EC.String("role", "readWrite"),
+					bson.EC.String("db", "test"),
+				),
+			),
+		),
+	)
+	require.NoError(t, err)
+
+	var callbackInvocations int
+	callback := func(_ context.Context, info clientopt.IDPInfo) (clientopt.OIDCCredential, error) {
+		callbackInvocations++
+		data, readErr := ioutil.ReadFile(tokenFile)
+		if readErr != nil {
+			return clientopt.OIDCCredential{}, readErr
+		}
+		return clientopt.OIDCCredential{AccessToken: strings.TrimSpace(string(data))}, nil
+	}
+
+	baseConnString := testutil.ConnString(t)
+	cs := fmt.Sprintf("%s&authMechanism=MONGODB-OIDC", baseConnString.String())
+
+	authClient, err := NewClientWithOptions(cs, clientopt.OIDCCallback(callback))
+	require.NoError(t, err)
+
+	err = authClient.Connect(context.Background())
+	require.NoError(t, err)
+
+	require.True(t, callbackInvocations > 0)
+
+	db = authClient.Database("test")
+	rdr, err := db.RunCommand(
+		context.Background(),
+		bson.NewDocument(
+			bson.EC.Int32("connectionStatus", 1),
+		),
+	)
+	require.NoError(t, err)
+
+	users, err := rdr.LookupErr("authInfo", "authenticatedUsers")
+	require.NoError(t, err)
+
+	array := users.Array()
+	elems, err := array.Elements()
+	require.NoError(t, err)
+
+	for _, v := range elems {
+		rdr := v.Value().Document()
+		var u struct {
+			User string
+			DB   string
+		}
+
+		if err := bson.Unmarshal(rdr, &u); err != nil {
+			continue
+		}
+
+		if u.User == user && u.DB == "$external" {
+			return
+		}
+	}
+
+	t.Error("unable to find authenticated user")
+}
@@ -0,0 +1,96 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package clientopt
+
+import "time"
+
+// Option configures a Client under construction by mongo.NewClientWithOptions.
+type Option interface {
+	ConfigureClient(*Client) error
+}
+
+// Client accumulates the values passed to mongo.NewClientWithOptions before
+// they are merged onto the parsed connection string.
+type Client struct {
+	ReplicaSet             string
+	MaxConnIdleTime        time.Duration
+	LocalThreshold         time.Duration
+	ServerSelectionTimeout time.Duration
+
+	OIDCCallback OIDCCallbackFunc
+	Dialer       DialerFunc
+
+	CredentialProvider CredentialProvider
+	RetryOnAuthFailure bool
+
+	TLSFIPSMode bool
+}
+
+// NewClient applies opts, in order, to a fresh Client.
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{}
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+		if err := opt.ConfigureClient(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+type replicaSet string
+
+func (r replicaSet) ConfigureClient(c *Client) error {
+	c.ReplicaSet = string(r)
+	return nil
+}
+
+// ReplicaSet sets the name of the replica set to connect to.
+func ReplicaSet(name string) Option {
+	return replicaSet(name)
+}
+
+type maxConnIdleTime time.Duration
+
+func (m maxConnIdleTime) ConfigureClient(c *Client) error {
+	c.MaxConnIdleTime = time.Duration(m)
+	return nil
+}
+
+// MaxConnIdleTime sets the maximum time a pooled connection may sit idle
+// before it is closed.
+func MaxConnIdleTime(d time.Duration) Option {
+	return maxConnIdleTime(d)
+}
+
+type localThreshold time.Duration
+
+func (l localThreshold) ConfigureClient(c *Client) error {
+	c.LocalThreshold = time.Duration(l)
+	return nil
+}
+
+// LocalThreshold sets the latency window used for server selection among
+// otherwise-suitable servers.
+func LocalThreshold(d time.Duration) Option {
+	return localThreshold(d)
+}
+
+type serverSelectionTimeout time.Duration
+
+func (s serverSelectionTimeout) ConfigureClient(c *Client) error {
+	c.ServerSelectionTimeout = time.Duration(s)
+	return nil
+}
+
+// ServerSelectionTimeout sets how long server selection waits for a
+// suitable server before giving up.
+func ServerSelectionTimeout(d time.Duration) Option {
+	return serverSelectionTimeout(d)
+}
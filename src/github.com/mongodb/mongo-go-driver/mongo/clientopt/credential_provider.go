@@ -0,0 +1,36 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package clientopt
+
+import "context"
+
+// CredentialProvider is invoked lazily before each authentication handshake
+// so applications can source credentials from a secrets manager (e.g. a
+// Vault database secrets engine) instead of embedding them in the URI.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+type credentialProvider struct {
+	Provider           CredentialProvider
+	RetryOnAuthFailure bool
+}
+
+func (c *credentialProvider) ConfigureClient(client *Client) error {
+	client.CredentialProvider = c.Provider
+	client.RetryOnAuthFailure = c.RetryOnAuthFailure
+	return nil
+}
+
+// WithCredentialProvider configures a CredentialProvider that overrides any
+// username/password embedded in the connection URI. When retryOnAuthFailure
+// is true, the driver re-invokes the provider and retries the handshake once
+// if authentication fails. Authentication runs only in Client.Connect, so
+// credentials that rotate after Connect are not picked up.
+func WithCredentialProvider(provider CredentialProvider, retryOnAuthFailure bool) Option {
+	return &credentialProvider{Provider: provider, RetryOnAuthFailure: retryOnAuthFailure}
+}
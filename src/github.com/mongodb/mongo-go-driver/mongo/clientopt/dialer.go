@@ -0,0 +1,34 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package clientopt
+
+import (
+	"context"
+	"net"
+)
+
+// DialerFunc dials a network connection for the driver, overriding the
+// default TCP/Unix dialer. network and addr are the values the topology
+// dialer would otherwise pass to net.Dial, so a custom implementation can
+// still special-case "unix" addresses itself if desired.
+type DialerFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+type dialer struct {
+	Dialer DialerFunc
+}
+
+func (d *dialer) ConfigureClient(c *Client) error {
+	c.Dialer = d.Dialer
+	return nil
+}
+
+// Dialer overrides the network dialer used to establish connections to
+// servers in the topology, including the Unix domain socket path used for
+// mongodb:// URIs whose host is a filesystem path.
+func Dialer(d DialerFunc) Option {
+	return &dialer{Dialer: d}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package clientopt
+
+import (
+	"context"
+	"time"
+)
+
+// IDPInfo describes the identity provider context passed to an OIDCCallback
+// so that a human or machine workflow can decide how to obtain a token. The
+// driver fills in only Issuer; ClientID and RequestScopes are left empty.
+type IDPInfo struct {
+	// Issuer is the OIDC issuer URL, taken from the OIDC_ISSUER entry of the
+	// connection string's authMechanismProperties.
+	Issuer string
+	// ClientID is the OAuth client id the token should be requested for, if any.
+	ClientID string
+	// RequestScopes lists the OAuth scopes the server is requesting.
+	RequestScopes []string
+}
+
+// OIDCCredential is the result of an OIDCCallback invocation. AccessToken is
+// required. ExpiresAt is optional and, when set, lets the driver reuse the
+// cached token instead of re-invoking the callback until the token is near
+// expiry. RefreshToken is optional and is not used by the driver.
+type OIDCCredential struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// OIDCCallbackFunc is invoked by the driver when Client.Connect authenticates
+// with MONGODB-OIDC and no unexpired cached token is available, and once more
+// if that authentication is answered with a ReauthenticationRequired (391)
+// error. Operations run after Connect do not re-authenticate, so a token
+// that expires or a 391 error returned to them does not invoke it.
+type OIDCCallbackFunc func(ctx context.Context, info IDPInfo) (OIDCCredential, error)
+
+// oidcCallback is an Option that configures the callback used to mint
+// MONGODB-OIDC access tokens for the human/callback workflow. It has no
+// effect unless authMechanism=MONGODB-OIDC is selected, either explicitly
+// in the URI or because this option was provided.
+type oidcCallback struct {
+	Callback OIDCCallbackFunc
+}
+
+func (o *oidcCallback) ConfigureClient(c *Client) error {
+	c.OIDCCallback = o.Callback
+	return nil
+}
+
+// OIDCCallback configures the callback the driver invokes to obtain
+// MONGODB-OIDC access tokens. Providing this option implies
+// authMechanism=MONGODB-OIDC if no mechanism was set on the URI.
+func OIDCCallback(callback OIDCCallbackFunc) Option {
+	return &oidcCallback{Callback: callback}
+}
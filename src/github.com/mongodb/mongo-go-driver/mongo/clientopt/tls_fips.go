@@ -0,0 +1,24 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package clientopt
+
+type tlsFIPSMode struct {
+	Enabled bool
+}
+
+func (t *tlsFIPSMode) ConfigureClient(c *Client) error {
+	c.TLSFIPSMode = t.Enabled
+	return nil
+}
+
+// TLSFIPSMode requests that the client restrict TLS to FIPS 140-2 approved
+// ciphers and refuse to start if the binary wasn't built with a FIPS-capable
+// TLS backend (-tags openssl_fips). It can also be set via the
+// tlsFIPSMode=true URI parameter.
+func TLSFIPSMode(enabled bool) Option {
+	return &tlsFIPSMode{Enabled: enabled}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import "github.com/mongodb/mongo-go-driver/bson"
+
+// commandError represents a server-reported command failure that carries a
+// numeric error code, the shape used to recognize errors such as
+// ReauthenticationRequired (391) and ResumableChangeStreamError (286).
+type commandError struct {
+	Code    int32
+	Message string
+}
+
+func (e commandError) Error() string {
+	return e.Message
+}
+
+// commandErrorFromReply builds a commandError from a command reply
+// document whose "ok" field is not 1, returning nil if rdr doesn't
+// represent a command error.
+func commandErrorFromReply(rdr bson.Reader) error {
+	okVal, err := rdr.Lookup("ok")
+	if err == nil && okVal.Value().Double() == 1 {
+		return nil
+	}
+
+	var code int32
+	if codeVal, err := rdr.Lookup("code"); err == nil {
+		code = codeVal.Value().Int32()
+	}
+
+	var message string
+	if msgVal, err := rdr.Lookup("errmsg"); err == nil {
+		message = msgVal.Value().StringValue()
+	}
+
+	return commandError{Code: code, Message: message}
+}
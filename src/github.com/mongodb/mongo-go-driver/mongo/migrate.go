@@ -0,0 +1,241 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+	"github.com/mongodb/mongo-go-driver/core/writeconcern"
+	"github.com/mongodb/mongo-go-driver/mongo/updateopt"
+)
+
+// migrationsCollection holds the single document that tracks the currently
+// applied Migration.Version for a database.
+const migrationsCollection = "schema_migrations"
+
+// versionDocID is the fixed _id of the version-tracking document.
+const versionDocID = "version"
+
+// lockID is the fixed _id of the advisory lock document, stored in the same
+// collection as the version document.
+const lockID = "_lock"
+
+// lockTTL bounds how long a migration run may hold the advisory lock before
+// it is considered abandoned and eligible to be reclaimed by another run.
+const lockTTL = 5 * time.Minute
+
+// ErrLockTimeout is returned by Migrate when another instance already holds
+// an unexpired migration lock. Migrate does not wait for the lock; it
+// returns ErrLockTimeout on the first failed attempt.
+var ErrLockTimeout = errors.New("mongo: timed out waiting for migration lock")
+
+// duplicateKeyErrorCode is the server error code for a unique-index
+// collision, which is how acquireMigrationLock's upsert reports that a
+// still-valid lock is already held by another instance.
+const duplicateKeyErrorCode = 11000
+
+// Migration is a single, ordered schema change applied by Client.Migrate. Up
+// must be idempotent with respect to Version so that a partially-applied
+// migration can be safely retried. Down reverses Up and is used when
+// migrating backward.
+type Migration struct {
+	Version int
+	Up      func(ctx context.Context, db *Database) error
+	Down    func(ctx context.Context, db *Database) error
+}
+
+// Migrate applies migrations, in ascending Version order, against dbName
+// until the applied version reaches targetVersion, or reverses them in
+// descending order if targetVersion is below the currently applied version.
+// It holds a TTL-backed advisory lock for the duration of the run, acquired
+// via an upsert on a _lock document in the schema_migrations collection, so
+// that concurrent application instances don't run migrations twice, and
+// persists the applied version after each successful step so that a partial
+// failure can be safely resumed by calling Migrate again.
+func (c *Client) Migrate(ctx context.Context, dbName string, migrations []Migration, targetVersion int) error {
+	db := c.Database(dbName)
+	db.writeConcern = writeconcern.New(writeconcern.WMajority())
+
+	if err := acquireMigrationLock(ctx, db); err != nil {
+		return err
+	}
+	defer releaseMigrationLock(ctx, db)
+
+	current, err := appliedMigrationVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range pendingMigrationSteps(migrations, current, targetVersion) {
+		if err := runMigrationStep(ctx, db, s); err != nil {
+			return fmt.Errorf("mongo: migrate: version %d: %w", s.migration.Version, err)
+		}
+		if err := setAppliedMigrationVersion(ctx, db, s.appliedVersion()); err != nil {
+			return fmt.Errorf("mongo: migrate: recording version %d: %w", s.migration.Version, err)
+		}
+	}
+
+	return nil
+}
+
+type migrationStep struct {
+	migration Migration
+	forward   bool
+}
+
+// appliedVersion returns the schema version that should be recorded once
+// this step completes successfully.
+func (s migrationStep) appliedVersion() int {
+	if s.forward {
+		return s.migration.Version
+	}
+	return s.migration.Version - 1
+}
+
+// pendingMigrationSteps computes the ordered list of migrations to run to
+// move from current to targetVersion: forward via Up when
+// targetVersion >= current, backward via Down otherwise.
+func pendingMigrationSteps(migrations []Migration, current, targetVersion int) []migrationStep {
+	var steps []migrationStep
+
+	if targetVersion >= current {
+		for _, m := range migrations {
+			if m.Version > current && m.Version <= targetVersion {
+				steps = append(steps, migrationStep{migration: m, forward: true})
+			}
+		}
+		return steps
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version <= current && m.Version > targetVersion {
+			steps = append(steps, migrationStep{migration: m, forward: false})
+		}
+	}
+	return steps
+}
+
+// runMigrationStep runs a single migration step's Up or Down function
+// against db, whose write concern Migrate has already set to majority, so
+// the step's writes are acknowledged by a majority of the replica set just
+// like the version bookkeeping that follows it.
+func runMigrationStep(ctx context.Context, db *Database, s migrationStep) error {
+	fn := s.migration.Up
+	if !s.forward {
+		fn = s.migration.Down
+	}
+	if fn == nil {
+		return fmt.Errorf("migration has no %s function", migrationDirection(s.forward))
+	}
+
+	return fn(ctx, db)
+}
+
+func migrationDirection(forward bool) string {
+	if forward {
+		return "Up"
+	}
+	return "Down"
+}
+
+// appliedMigrationVersion returns the currently applied schema version, or
+// 0 if no migrations have ever been applied to db.
+func appliedMigrationVersion(ctx context.Context, db *Database) (int, error) {
+	rdr, err := db.RunCommand(ctx, bson.NewDocument(
+		bson.EC.String("find", migrationsCollection),
+		bson.EC.SubDocumentFromElements("filter", bson.EC.String("_id", versionDocID)),
+		bson.EC.Int32("limit", 1),
+	))
+	if err != nil {
+		return 0, err
+	}
+
+	batch, err := rdr.LookupErr("cursor", "firstBatch")
+	if err != nil {
+		return 0, err
+	}
+
+	elems, err := batch.Array().Elements()
+	if err != nil || len(elems) == 0 {
+		// No document yet means no migrations have been applied.
+		return 0, nil
+	}
+
+	value, err := elems[0].Value().Document().LookupErr("version")
+	if err != nil {
+		return 0, nil
+	}
+
+	return int(value.Int32()), nil
+}
+
+// setAppliedMigrationVersion records version as the currently applied
+// schema version, upserting the tracking document on first use.
+func setAppliedMigrationVersion(ctx context.Context, db *Database, version int) error {
+	coll := db.Collection(migrationsCollection)
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.NewDocument(bson.EC.String("_id", versionDocID)),
+		bson.NewDocument(bson.EC.SubDocumentFromElements("$set", bson.EC.Int32("version", int32(version)))),
+		updateopt.Upsert(true),
+	)
+	return err
+}
+
+// acquireMigrationLock upserts the lock document with an expiry, succeeding
+// only if the lock is unheld or its previous holder's TTL has expired. A
+// still-valid lock causes the upsert's insert path to collide with the
+// existing _id, which UpdateOne surfaces as a duplicate-key error; that
+// specific case maps to ErrLockTimeout, while any other error (a dropped
+// connection, an auth failure, ctx expiring) propagates unchanged so it
+// isn't mistaken for a held lock.
+func acquireMigrationLock(ctx context.Context, db *Database) error {
+	coll := db.Collection(migrationsCollection)
+
+	_, err := coll.UpdateOne(
+		ctx,
+		bson.NewDocument(
+			bson.EC.String("_id", lockID),
+			bson.EC.SubDocumentFromElements("expiresAt", bson.EC.DateTime("$lte", time.Now().Unix()*1000)),
+		),
+		bson.NewDocument(bson.EC.SubDocumentFromElements("$set",
+			bson.EC.DateTime("expiresAt", time.Now().Add(lockTTL).Unix()*1000),
+		)),
+		updateopt.Upsert(true),
+	)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return ErrLockTimeout
+		}
+		return err
+	}
+
+	return nil
+}
+
+// isDuplicateKeyError reports whether err is a unique-index collision, as
+// opposed to some other command or network failure.
+func isDuplicateKeyError(err error) bool {
+	cmdErr, ok := err.(commandError)
+	return ok && cmdErr.Code == duplicateKeyErrorCode
+}
+
+// releaseMigrationLock drops the expiry on the lock document so the next
+// Migrate call doesn't have to wait out lockTTL.
+func releaseMigrationLock(ctx context.Context, db *Database) {
+	coll := db.Collection(migrationsCollection)
+	_, _ = coll.UpdateOne(
+		ctx,
+		bson.NewDocument(bson.EC.String("_id", lockID)),
+		bson.NewDocument(bson.EC.SubDocumentFromElements("$set", bson.EC.DateTime("expiresAt", int64(0)))),
+	)
+}
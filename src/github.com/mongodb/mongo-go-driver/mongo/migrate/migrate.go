@@ -0,0 +1,67 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// Package migrate collects example Migration steps for mongo.Client.Migrate
+// that reshape collection indexes and backfill fields across tenants, the
+// use case that motivates most real-world schema migrations: compound
+// indexes changing shape as query patterns evolve, and fields that need a
+// default value added retroactively.
+package migrate
+
+import (
+	"context"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+	"github.com/mongodb/mongo-go-driver/core/index"
+	"github.com/mongodb/mongo-go-driver/mongo"
+)
+
+// Migration is an alias for mongo.Migration so callers can depend on this
+// package alone when organizing their migration steps.
+type Migration = mongo.Migration
+
+// ReshapeIndex returns a Migration that drops oldKeys (if present) and
+// creates a new compound index with newKeys on collName, the shape most
+// real-world index migrations take as query patterns evolve.
+func ReshapeIndex(version int, collName string, oldKeys, newKeys bson.Keys) Migration {
+	return Migration{
+		Version: version,
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			coll := db.Collection(collName)
+			_ = coll.Indexes().DropOne(ctx, index.Name(oldKeys))
+			_, err := coll.Indexes().CreateOne(ctx, index.Model{Keys: newKeys})
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			coll := db.Collection(collName)
+			_ = coll.Indexes().DropOne(ctx, index.Name(newKeys))
+			_, err := coll.Indexes().CreateOne(ctx, index.Model{Keys: oldKeys})
+			return err
+		},
+	}
+}
+
+// BackfillField returns a Migration that sets field to value on every
+// document in collName that doesn't already have it set, across all
+// tenants. Down is a no-op since removing a backfilled default isn't, in
+// general, safe to automate.
+func BackfillField(version int, collName, field string, value interface{}) Migration {
+	return Migration{
+		Version: version,
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			coll := db.Collection(collName)
+			_, err := coll.UpdateMany(
+				ctx,
+				bson.NewDocument(bson.EC.SubDocumentFromElements(field, bson.EC.Boolean("$exists", false))),
+				bson.NewDocument(bson.EC.SubDocumentFromElements("$set", bson.EC.Interface(field, value))),
+			)
+			return err
+		},
+		Down: func(ctx context.Context, db *mongo.Database) error {
+			return nil
+		},
+	}
+}
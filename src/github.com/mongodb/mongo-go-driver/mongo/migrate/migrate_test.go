@@ -0,0 +1,118 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/mongodb/mongo-go-driver/internal/testutil"
+	"github.com/mongodb/mongo-go-driver/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func testClient(t *testing.T) *mongo.Client {
+	c, err := mongo.NewClient(testutil.ConnString(t).String())
+	require.NoError(t, err)
+	require.NoError(t, c.Connect(context.Background()))
+	return c
+}
+
+func TestMigrate_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	c := testClient(t)
+	dbName := "migrate_partial_failure"
+
+	var ran []int
+	migrations := []mongo.Migration{
+		{Version: 1, Up: func(ctx context.Context, db *mongo.Database) error {
+			ran = append(ran, 1)
+			return nil
+		}},
+		{Version: 2, Up: func(ctx context.Context, db *mongo.Database) error {
+			ran = append(ran, 2)
+			return errors.New("boom")
+		}},
+		{Version: 3, Up: func(ctx context.Context, db *mongo.Database) error {
+			ran = append(ran, 3)
+			return nil
+		}},
+	}
+
+	err := c.Migrate(ctx, dbName, migrations, 3)
+	require.Error(t, err)
+	require.Equal(t, []int{1, 2}, ran)
+}
+
+func TestMigrate_IdempotentRerun(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	c := testClient(t)
+	dbName := "migrate_idempotent_rerun"
+
+	var applied int
+	migrations := []mongo.Migration{
+		{Version: 1, Up: func(ctx context.Context, db *mongo.Database) error {
+			applied++
+			return nil
+		}},
+	}
+
+	require.NoError(t, c.Migrate(ctx, dbName, migrations, 1))
+	require.NoError(t, c.Migrate(ctx, dbName, migrations, 1))
+	require.Equal(t, 1, applied)
+}
+
+func TestMigrate_LockTimeout(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	c := testClient(t)
+	dbName := "migrate_lock_timeout"
+
+	blocked := make(chan struct{})
+	release := make(chan struct{})
+	var once sync.Once
+	migrations := []mongo.Migration{
+		{Version: 1, Up: func(ctx context.Context, db *mongo.Database) error {
+			// Only the instance that actually wins the advisory lock should
+			// reach here; guard with sync.Once so a locking regression
+			// surfaces as a failed assertion rather than a panic from
+			// closing blocked twice.
+			once.Do(func() { close(blocked) })
+			<-release
+			return nil
+		}},
+	}
+
+	go func() {
+		_ = c.Migrate(ctx, dbName, migrations, 1)
+	}()
+
+	<-blocked
+	err := c.Migrate(ctx, dbName, migrations, 1)
+	require.Equal(t, mongo.ErrLockTimeout, err)
+
+	close(release)
+}
@@ -0,0 +1,50 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+	"github.com/mongodb/mongo-go-driver/mongo/updateopt"
+)
+
+// NewCollectionResumeTokenStore returns a ResumeTokenStore that persists
+// each namespace's resume token as a document in coll, keyed by namespace.
+// Unlike the default in-memory store, this survives process restarts, which
+// is what a durable CDC pipeline built on Client.Subscribe needs.
+func NewCollectionResumeTokenStore(coll *Collection) ResumeTokenStore {
+	return &collectionResumeTokenStore{coll: coll}
+}
+
+type collectionResumeTokenStore struct {
+	coll *Collection
+}
+
+func (s *collectionResumeTokenStore) SaveResumeToken(ctx context.Context, namespace string, token bson.Reader) error {
+	_, err := s.coll.UpdateOne(
+		ctx,
+		bson.NewDocument(bson.EC.String("_id", namespace)),
+		bson.NewDocument(bson.EC.SubDocumentFromElements("$set", bson.EC.SubDocumentFromReader("resumeToken", token))),
+		updateopt.Upsert(true),
+	)
+	return err
+}
+
+func (s *collectionResumeTokenStore) LoadResumeToken(ctx context.Context, namespace string) (bson.Reader, error) {
+	rdr, err := s.coll.findOneRaw(ctx, bson.NewDocument(bson.EC.String("_id", namespace)))
+	if err != nil {
+		return nil, nil
+	}
+
+	value, err := rdr.LookupErr("resumeToken")
+	if err != nil {
+		return nil, nil
+	}
+
+	return value.Document().Reader, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+)
+
+// saslConversation drives a saslStart/saslContinue exchange for mechanisms
+// that authenticate with a single bearer payload, such as MONGODB-OIDC,
+// rather than the multi-step challenge/response used by SCRAM.
+type saslConversation struct {
+	mechanism string
+	payload   []byte
+}
+
+func newSaslConversation(mechanism string, payload []byte) *saslConversation {
+	return &saslConversation{mechanism: mechanism, payload: payload}
+}
+
+// run executes the saslStart/saslContinue round trip against db, looping
+// saslContinue calls until the server reports the conversation done.
+func (c *saslConversation) run(ctx context.Context, db *Database) error {
+	rdr, err := db.RunCommand(ctx, bson.NewDocument(
+		bson.EC.Int32("saslStart", 1),
+		bson.EC.String("mechanism", c.mechanism),
+		bson.EC.Binary("payload", c.payload),
+	))
+	if err != nil {
+		return err
+	}
+	if err := commandErrorFromReply(rdr); err != nil {
+		return err
+	}
+
+	for {
+		if done, derr := rdr.LookupErr("done"); derr == nil && done.Boolean() {
+			return nil
+		}
+
+		conversationID, err := rdr.LookupErr("conversationId")
+		if err != nil {
+			return err
+		}
+
+		rdr, err = db.RunCommand(ctx, bson.NewDocument(
+			bson.EC.Int32("saslContinue", 1),
+			bson.EC.Int32("conversationId", conversationID.Int32()),
+			bson.EC.Binary("payload", []byte{}),
+		))
+		if err != nil {
+			return err
+		}
+		if err := commandErrorFromReply(rdr); err != nil {
+			return err
+		}
+	}
+}
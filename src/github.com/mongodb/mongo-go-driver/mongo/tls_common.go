@@ -0,0 +1,62 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+
+	"github.com/mongodb/mongo-go-driver/core/connstring"
+)
+
+// errFIPSModeUnavailable is returned by newTLSWrapper when TLSFIPSMode(true)
+// is set but this binary wasn't built with a FIPS-capable TLS backend (see
+// tls_go.go and tls_openssl.go).
+var errFIPSModeUnavailable = errors.New("mongo: tlsFIPSMode=true requires building with -tags openssl_fips")
+
+// tlsConfigFromConnString builds the *tls.Config a connection to cs's
+// deployment should use: the server name to verify against, any configured
+// CA, and any configured client certificate. It is used by the crypto/tls
+// backend (tls_go.go); the OpenSSL backend (tls_openssl.go) loads the CA and
+// client certificate into its openssl.Ctx itself.
+func tlsConfigFromConnString(cs connstring.ConnString) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: cs.SSLInsecure}
+
+	if len(cs.Hosts) > 0 {
+		if host, _, err := net.SplitHostPort(cs.Hosts[0]); err == nil {
+			cfg.ServerName = host
+		} else {
+			cfg.ServerName = cs.Hosts[0]
+		}
+	}
+
+	if cs.SSLCaFile != "" {
+		pem, err := ioutil.ReadFile(cs.SSLCaFile)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("mongo: no certificates found in %s", cs.SSLCaFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if cs.SSLClientCertificateKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cs.SSLClientCertificateKeyFile, cs.SSLClientCertificateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
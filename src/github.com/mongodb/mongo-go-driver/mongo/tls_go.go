@@ -0,0 +1,53 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// +build !openssl_fips
+
+package mongo
+
+import (
+	"crypto/tls"
+	"net"
+
+	"github.com/mongodb/mongo-go-driver/core/connstring"
+)
+
+// tlsBackendName identifies which TLS implementation this build was
+// compiled against.
+const tlsBackendName = "go"
+
+// fipsCapable reports whether the TLS backend this binary was built against
+// can operate in FIPS 140-2 approved mode. The standard library's crypto/tls
+// is never FIPS-capable on its own, so a client with TLSFIPSMode(true) must
+// be built with -tags openssl_fips.
+func fipsCapable() bool {
+	return false
+}
+
+// newTLSWrapper returns a function that upgrades a raw connection to TLS by
+// performing the handshake through crypto/tls, configured with cs's server
+// name, CA, and client certificate. It refuses to build one if fipsMode is
+// requested, since crypto/tls's handshake is never FIPS-capable on its own;
+// building with -tags openssl_fips is required for TLSFIPSMode(true), which
+// routes the handshake through OpenSSL instead.
+func newTLSWrapper(fipsMode bool, cs connstring.ConnString) (func(net.Conn) (net.Conn, error), error) {
+	if fipsMode {
+		return nil, errFIPSModeUnavailable
+	}
+
+	cfg, err := tlsConfigFromConnString(cs)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(conn net.Conn) (net.Conn, error) {
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		return tlsConn, nil
+	}, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// +build openssl_fips
+
+package mongo
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/mongodb/mongo-go-driver/core/connstring"
+	"github.com/spacemonkeygo/openssl"
+)
+
+// tlsBackendName identifies which TLS implementation this build was
+// compiled against.
+const tlsBackendName = "openssl"
+
+// fipsApprovedOpenSSLCipherNames are the FIPS 140-2 approved ciphers,
+// named the way OpenSSL itself reports them (and the way MongoDB's
+// serverStatus security.SSLServerCipherSuite reports them), not the
+// TLS_-prefixed names crypto/tls uses.
+var fipsApprovedOpenSSLCipherNames = []string{
+	"ECDHE-RSA-AES128-GCM-SHA256",
+	"ECDHE-RSA-AES256-GCM-SHA384",
+	"AES128-GCM-SHA256",
+	"AES256-GCM-SHA384",
+}
+
+// fipsApprovedOpenSSLCipherList is fipsApprovedOpenSSLCipherNames joined in
+// the colon-separated form openssl.Ctx.SetCipherList expects.
+var fipsApprovedOpenSSLCipherList = strings.Join(fipsApprovedOpenSSLCipherNames, ":")
+
+var (
+	fipsModeOnce sync.Once
+	fipsModeErr  error
+)
+
+// enableFIPSMode switches the process linked against OpenSSL into FIPS mode
+// exactly once. openssl.FIPSModeSet is a global, mutating, process-wide
+// switch, not a side-effect-free capability probe, so it must not be
+// re-invoked on every newTLSWrapper call — sync.Once makes repeated calls
+// cheap and idempotent.
+func enableFIPSMode() error {
+	fipsModeOnce.Do(func() {
+		fipsModeErr = openssl.FIPSModeSet(true)
+	})
+	return fipsModeErr
+}
+
+// fipsCapable reports whether the OpenSSL library linked into this binary
+// can be switched into FIPS mode.
+func fipsCapable() bool {
+	return enableFIPSMode() == nil
+}
+
+// newTLSWrapper returns a function that upgrades a raw connection to TLS by
+// performing the handshake through the linked OpenSSL library via
+// openssl.Client, configured with cs's CA and client certificate, so FIPS
+// mode actually governs the code path doing the handshake instead of
+// merely filtering a crypto/tls cipher list on top of the non-FIPS-validated
+// Go TLS stack. When fipsMode is set, cipher negotiation is restricted to
+// fipsApprovedOpenSSLCipherList.
+func newTLSWrapper(fipsMode bool, cs connstring.ConnString) (func(net.Conn) (net.Conn, error), error) {
+	if fipsMode && !fipsCapable() {
+		return nil, errFIPSModeUnavailable
+	}
+
+	ctx, err := openssl.NewCtx()
+	if err != nil {
+		return nil, err
+	}
+
+	if fipsMode {
+		if err := ctx.SetCipherList(fipsApprovedOpenSSLCipherList); err != nil {
+			return nil, err
+		}
+	}
+
+	if cs.SSLCaFile != "" {
+		if err := ctx.LoadVerifyLocations(cs.SSLCaFile, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	if cs.SSLClientCertificateKeyFile != "" {
+		if err := ctx.UseCertificateChainFile(cs.SSLClientCertificateKeyFile); err != nil {
+			return nil, err
+		}
+		if err := ctx.UsePrivateKeyFile(cs.SSLClientCertificateKeyFile, openssl.FiletypePEM); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(conn net.Conn) (net.Conn, error) {
+		return openssl.Client(conn, ctx)
+	}, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+// +build openssl_fips
+
+package mongo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+	"github.com/mongodb/mongo-go-driver/core/connstring"
+	"github.com/mongodb/mongo-go-driver/internal/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_TLSConnection_FIPS(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip()
+	}
+
+	caFile := os.Getenv("MONGO_GO_DRIVER_CA_FILE")
+
+	if len(caFile) == 0 {
+		t.Skip()
+	}
+
+	baseConnString := testutil.ConnString(t)
+	cs := testutil.AddOptionsToURI(baseConnString.String(), "tlsFIPSMode=true")
+
+	c, err := NewClient(cs)
+	require.NoError(t, err)
+
+	err = c.Connect(context.Background())
+	require.NoError(t, err)
+
+	db := c.Database("test")
+	result, err := db.RunCommand(context.Background(), bson.NewDocument(bson.EC.Int32("serverStatus", 1)))
+	require.NoError(t, err)
+
+	security, err := result.LookupErr("security")
+	require.Nil(t, err)
+	require.Equal(t, security.Type, bson.TypeEmbeddedDocument)
+
+	cipher, err := security.Document().LookupErr("SSLServerCipherSuite")
+	require.NoError(t, err)
+	require.True(t, strings.Contains(fipsApprovedOpenSSLCipherList, cipher.StringValue()))
+}
+
+func TestNewTLSWrapper_FIPSMode(t *testing.T) {
+	t.Parallel()
+
+	wrapper, err := newTLSWrapper(true, connstring.ConnString{})
+
+	if !fipsCapable() {
+		require.Equal(t, errFIPSModeUnavailable, err)
+		require.Nil(t, wrapper)
+		return
+	}
+
+	require.NoError(t, err)
+	require.NotNil(t, wrapper)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+// isUnixSocketAddr reports whether host looks like a filesystem path rather
+// than a hostname, which is how the driver recognizes a
+// mongodb:// URI that should be dialed as a Unix domain socket, e.g.
+// "mongodb://%2Ftmp%2Fmongodb-27017.sock". host may be a bare path or a
+// "path:port"-style address, since dialers conventionally append a port
+// before calling down to the network layer.
+func isUnixSocketAddr(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.HasSuffix(host, ".sock") || strings.HasPrefix(host, "/")
+}
+
+// defaultDialer dials network/addr using net.Dialer, except that an addr
+// recognized by isUnixSocketAddr is always dialed over "unix" regardless of
+// the network the caller requested, since Unix domain sockets never appear
+// in SRV or replica set discovery as anything but a single fixed path.
+func defaultDialer(ctx context.Context, network, addr string) (net.Conn, error) {
+	if isUnixSocketAddr(addr) {
+		network = "unix"
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			addr = h
+		}
+	}
+
+	d := net.Dialer{}
+	return d.DialContext(ctx, network, addr)
+}
+
+// dialerFor returns the client's configured Dialer, falling back to
+// defaultDialer so Unix domain socket addresses work out of the box for
+// both single-server and replica-set discovery paths.
+func (c *Client) dialerFor() func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if c.dialer != nil {
+		return c.dialer
+	}
+	return defaultDialer
+}
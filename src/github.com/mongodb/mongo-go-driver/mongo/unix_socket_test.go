@@ -0,0 +1,64 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsUnixSocketAddr(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"/tmp/mongodb-27017.sock":       true,
+		"/tmp/mongodb-27017.sock:27017": true,
+		"localhost:27017":               false,
+		"mongodb-27017.sock":            true,
+		"mongodb-27017.sock:27017":      true,
+		"127.0.0.1:27017":               false,
+	}
+
+	for addr, want := range cases {
+		require.Equal(t, want, isUnixSocketAddr(addr), addr)
+	}
+}
+
+func TestDefaultDialer_UnixSocket(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "mongo-go-driver-unix-socket-test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sockPath := filepath.Join(dir, "mongodb-27017.sock")
+
+	ln, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	defer ln.Close()
+
+	accepted := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			conn.Close()
+		}
+		close(accepted)
+	}()
+
+	conn, err := defaultDialer(context.Background(), "tcp", sockPath+":27017")
+	require.NoError(t, err)
+	conn.Close()
+
+	<-accepted
+}
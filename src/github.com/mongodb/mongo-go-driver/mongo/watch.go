@@ -0,0 +1,147 @@
+// Copyright (C) MongoDB, Inc. 2017-present.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at http://www.apache.org/licenses/LICENSE-2.0
+
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/mongodb/mongo-go-driver/bson"
+)
+
+// resumableChangeStreamErrorCode is the server error code that indicates a
+// change stream cursor was invalidated in a way the driver can recover from
+// by resuming with the last known resume token.
+const resumableChangeStreamErrorCode = 286
+
+// cursorNotFoundErrorCode is the server error code a getMore receives once
+// its cursor has been killed or has timed out; Subscribe recovers from it by
+// reopening the stream from the last persisted resume token.
+const cursorNotFoundErrorCode = 43
+
+// ChangeEvent is a single document from a change stream, decoded enough to
+// drive cache invalidation and CDC handlers without requiring callers to
+// know the full change stream document shape up front.
+type ChangeEvent struct {
+	ID            bson.Reader
+	OperationType string
+	Namespace     struct {
+		DB   string
+		Coll string
+	}
+	FullDocument bson.Reader
+	Raw          bson.Reader
+}
+
+// ResumeTokenStore persists the resume token for a change stream so that
+// Client.Subscribe can pick up where it left off after a process restart,
+// not just after an in-process reconnect.
+type ResumeTokenStore interface {
+	SaveResumeToken(ctx context.Context, namespace string, token bson.Reader) error
+	LoadResumeToken(ctx context.Context, namespace string) (bson.Reader, error)
+}
+
+// memoryResumeTokenStore is the default ResumeTokenStore. Subscribe creates
+// a fresh one per call, so its tokens last only as long as that call. It is
+// adequate for cache-invalidation use cases
+// where missing events after a restart are acceptable, but not for
+// durable CDC pipelines, which should supply a persistent ResumeTokenStore.
+type memoryResumeTokenStore struct {
+	tokens map[string]bson.Reader
+}
+
+func newMemoryResumeTokenStore() *memoryResumeTokenStore {
+	return &memoryResumeTokenStore{tokens: make(map[string]bson.Reader)}
+}
+
+func (s *memoryResumeTokenStore) SaveResumeToken(_ context.Context, namespace string, token bson.Reader) error {
+	s.tokens[namespace] = token
+	return nil
+}
+
+func (s *memoryResumeTokenStore) LoadResumeToken(_ context.Context, namespace string) (bson.Reader, error) {
+	return s.tokens[namespace], nil
+}
+
+// Watch opens a change stream against the client's entire deployment using
+// pipeline and opts. Use Client.Subscribe instead when only a single
+// "db.collection" namespace matters, since Watch here is deployment-wide.
+func (c *Client) Watch(ctx context.Context, pipeline *bson.Array, opts ...changeStreamOption) (*ChangeStream, error) {
+	return newChangeStream(ctx, c.Database("admin"), int32(1), pipeline, opts...)
+}
+
+// Subscribe maintains a change stream against namespace (given as
+// "db.collection") for the lifetime of ctx, invoking handler for every
+// event. The stream is opened directly against that collection, which is
+// how MongoDB scopes a change stream to a single namespace rather than a
+// whole database or deployment. Subscribe transparently reconnects on a
+// resumable change stream error and persists its resume token via store
+// after every event, so that a process restart resumes near where it left
+// off instead of re-delivering the entire collection history. A nil store
+// uses an in-memory default that lasts only for this Subscribe call.
+func (c *Client) Subscribe(ctx context.Context, namespace string, handler func(ChangeEvent), store ResumeTokenStore) error {
+	if store == nil {
+		store = newMemoryResumeTokenStore()
+	}
+
+	dbName, collName := splitNamespace(namespace)
+	db := c.Database(dbName)
+
+	for {
+		token, err := store.LoadResumeToken(ctx, namespace)
+		if err != nil {
+			return err
+		}
+
+		cs, err := newChangeStream(ctx, db, collName, nil, withResumeAfter(token))
+		if err != nil {
+			return err
+		}
+
+		err = consumeChangeStream(ctx, cs, namespace, handler, store)
+		cs.Close(ctx)
+
+		if err == nil {
+			return nil
+		}
+		if !isResumableChangeStreamError(err) {
+			return err
+		}
+
+		// ResumableChangeStreamError: back off briefly and reopen the
+		// stream from the last persisted resume token.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// consumeChangeStream drains cs until it errors or ctx is done, invoking
+// handler for each event and persisting the resume token after each one.
+func consumeChangeStream(ctx context.Context, cs *ChangeStream, namespace string, handler func(ChangeEvent), store ResumeTokenStore) error {
+	for cs.Next(ctx) {
+		var event ChangeEvent
+		if err := cs.Decode(&event); err != nil {
+			return err
+		}
+
+		handler(event)
+
+		if err := store.SaveResumeToken(ctx, namespace, cs.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	return cs.Err()
+}
+
+func isResumableChangeStreamError(err error) bool {
+	cmdErr, ok := err.(commandError)
+	return ok && (cmdErr.Code == resumableChangeStreamErrorCode || cmdErr.Code == cursorNotFoundErrorCode)
+}